@@ -2,220 +2,214 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
-	"sync"
-	"sync/atomic"
+
+	"github.com/Gadiguibou/primel-solver/pkg/primel"
 )
 
-// Interactive helper to find a solution to the game "Primel" (https://converged.yt/primel/)
-// The helper uses the simple heuristics of finding the most restrictive digit for each position
-// It is not optimal! A better helper would find the most restrictive guess considering all
-// remaining candidates. The optimal solver would find the best guess considering all possible
-// outcomes for each candidate.
+// Interactive helper to find a solution to numeric Wordle-style games such as "Primel"
+// (https://converged.yt/primel/), built as a thin CLI wrapper around the pkg/primel library.
+//
+// Three strategies are available via --strategy:
+//   - heuristic: the original "sum of remaining candidates" scorer. It is not optimal and is
+//     kept mostly for comparison, since it re-scans all candidates for every possible solution
+//     (effectively O(N^3)).
+//   - entropy: picks the guess that maximizes the Shannon entropy of the feedback-pattern
+//     distribution it induces over the remaining candidates, i.e. the guess that is expected to
+//     narrow down the candidate set the most.
+//   - minimax: picks the guess that minimizes the size of the largest feedback-pattern bucket,
+//     guaranteeing the best worst-case reduction.
+//
+// The game itself is configurable via --digits, --range and --set, so this same solver also
+// plays 6-digit Primel variants, Nerdle-style guessing games from a custom candidate file, etc.
+//
+// Running `primel-solver precompute --tree=path` builds the full decision tree ahead of time
+// (see precompute.go); passing `--tree=path` to the normal interactive run loads it and replaces
+// each Solver.Guess call with an O(1) lookup of the observed feedback.
 func main() {
-	// Calculate set of possible values
-	candidates := getPrimes(10000, 100000)
+	if len(os.Args) > 1 && os.Args[1] == "precompute" {
+		runPrecompute(os.Args[2:])
+		return
+	}
+
+	strategyFlag := flag.String("strategy", "entropy", "guess-scoring strategy to use: entropy, minimax or heuristic")
+	treeFlag := flag.String("tree", "", "path to a precomputed decision tree (see the precompute subcommand); recomputes every guess when empty")
+	digitsFlag := flag.Uint("digits", 5, "number of digits in a candidate")
+	rangeFlag := flag.String("range", "10000..100000", "half-open range of candidate values, as \"from..to\"")
+	setFlag := flag.String("set", "primes", "candidate set to draw guesses from: primes, squares, fibonacci, or file:path")
+	flag.Parse()
+
+	config, err := gameConfigFor(*digitsFlag, *rangeFlag, *setFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	solver, err := solverFor(*strategyFlag, config.Digits)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	candidates := config.Candidates()
 
-	// Find best guess according to the frequency of each digit per position
-	bestGuess := findBestGuess(candidates)
-	fmt.Printf("The best first guess is: %05d. The number of remaining candidates is %v\n", bestGuess, len(candidates))
+	var tree *decisionTree
+	if *treeFlag != "" {
+		tree, err = loadDecisionTree(*treeFlag, config.Digits, candidates)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not load decision tree: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var node *decisionNode
+	if tree != nil {
+		node = tree.Root
+	}
+	bestGuess := bestGuessFor(candidates, solver, node)
+	fmt.Printf("The best first guess is: %0*d. The number of remaining candidates is %v\n", int(config.Digits), bestGuess, len(candidates))
 
 	// Incorporate feedback and find next best guess
 	for {
-		feedbackPerDigit := readFeedbackForDigits(getDigits(bestGuess, 5))
-		if all(feedbackPerDigit, func(f feedback) bool { return f.feedbackType == feedbackTypeCorrect }) {
+		score := readScore(bestGuess, config.Digits)
+		if allCorrect(score) {
 			fmt.Printf("We found the correct number (\033[32m\033[1m%v\033[0m)! 🎉\n", bestGuess)
 			break
 		}
-		candidates = incorporateFeedback(feedbackPerDigit, candidates)
+
+		if node != nil {
+			node = node.Children[primel.FeedbackPattern(score)]
+			if node == nil {
+				fmt.Fprintf(os.Stderr, "The precomputed tree has no branch for this feedback; falling back to recomputing guesses.\n")
+			}
+		}
+		candidates = primel.Filter(candidates, bestGuess, score)
 		if len(candidates) == 0 {
 			fmt.Fprintf(os.Stderr, "No more candidates found!")
 			os.Exit(1)
 		}
-		bestGuess = findBestGuess(candidates)
-		fmt.Printf("The new best guess is: %05d. The number of remaining candidates is %v\n", bestGuess, len(candidates))
+		bestGuess = bestGuessFor(candidates, solver, node)
+		fmt.Printf("The new best guess is: %0*d. The number of remaining candidates is %v\n", int(config.Digits), bestGuess, len(candidates))
 	}
 }
 
-func findBestGuess(candidates []uint) uint {
-	var bestGuess uint
-	var bestGuessValue uint64
-	for i := 0; i < len(candidates); i++ {
-		candidateGuess := candidates[i]
-		guessValue := evaluateGuess(candidateGuess, candidates)
-		if guessValue > bestGuessValue {
-			bestGuess = candidateGuess
-		}
+// gameConfigFor resolves the --digits, --range and --set flags into a primel.GameConfig.
+func gameConfigFor(digits uint, rangeFlag string, setFlag string) (primel.GameConfig, error) {
+	from, to, err := parseRange(rangeFlag)
+	if err != nil {
+		return primel.GameConfig{}, err
 	}
-	return bestGuess
-}
 
-func evaluateGuess(guess uint, candidates []uint) uint64 {
-	var remainingCandidatesAfterGuess uint64
-	var wg sync.WaitGroup
-	for i := 0; i < len(candidates); i++ {
-		possibleSolution := candidates[i]
-		wg.Add(1)
-		go func() {
-			feedbackPerDigit := getFeedbackPerDigit(getDigits(guess, 5), getDigits(possibleSolution, 5))
-			newCandidates := incorporateFeedback(feedbackPerDigit, candidates)
-			atomic.AddUint64(&remainingCandidatesAfterGuess, uint64(len(newCandidates)))
-			wg.Done()
-		}()
+	set, err := candidateSetFor(setFlag)
+	if err != nil {
+		return primel.GameConfig{}, err
 	}
-	wg.Wait()
-	return remainingCandidatesAfterGuess
-}
 
-func getFeedbackPerDigit(guessDigits []uint, possibleSolutionDigits []uint) []feedback {
-	if len(guessDigits) != len(possibleSolutionDigits) {
-		panic("The length of the guess and the solution must be the same!")
+	config := primel.GameConfig{Digits: digits, From: from, To: to, Set: set}
+	if err := config.Validate(); err != nil {
+		return primel.GameConfig{}, err
 	}
-	feedbackPerDigit := make([]feedback, len(guessDigits))
+	return config, nil
+}
 
-	// Handle all correct digits first
-	for i := 0; i < len(guessDigits); i++ {
-		if guessDigits[i] == possibleSolutionDigits[i] {
-			feedbackPerDigit[i] = feedback{feedbackType: feedbackTypeCorrect, digit: guessDigits[i]}
-		}
+// parseRange parses a "from..to" range flag value.
+func parseRange(rangeFlag string) (from uint, to uint, err error) {
+	bounds := strings.SplitN(rangeFlag, "..", 2)
+	if len(bounds) != 2 {
+		return 0, 0, fmt.Errorf("invalid range %q: expected \"from..to\"", rangeFlag)
 	}
-
-	// Handle remaining (present and absent) digits
-	for i := 0; i < len(guessDigits); i++ {
-		// Correct digits have already been handled
-		if feedbackPerDigit[i].feedbackType == feedbackTypeCorrect {
-			continue
-		}
-
-		for j := 0; j < len(possibleSolutionDigits); j++ {
-			// Don't consider already correct digits to determine if the current digit is present or
-			// absent
-			if feedbackPerDigit[j].feedbackType == feedbackTypeCorrect {
-				continue
-			} else if possibleSolutionDigits[j] == guessDigits[i] {
-				feedbackPerDigit[i] = feedback{feedbackType: feedbackTypePresent, digit: guessDigits[i]}
-				break
-			} else if j == len(possibleSolutionDigits)-1 {
-				feedbackPerDigit[i] = feedback{feedbackType: feedbackTypeAbsent, digit: guessDigits[i]}
-			}
-		}
+	fromValue, err := strconv.ParseUint(bounds[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range %q: %w", rangeFlag, err)
 	}
-
-	return feedbackPerDigit
+	toValue, err := strconv.ParseUint(bounds[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range %q: %w", rangeFlag, err)
+	}
+	return uint(fromValue), uint(toValue), nil
 }
 
-func incorporateFeedback(feedbackPerDigit []feedback, candidates []uint) (newCandidates []uint) {
-	newCandidates = make([]uint, len(candidates))
-	copy(newCandidates, candidates)
-	var correctPositions []uint
-
-	// Process correct feedbacks first as they affect the other feedbacks
-	for i := 0; i < len(feedbackPerDigit); i++ {
-		if feedbackPerDigit[i].feedbackType == feedbackTypeCorrect {
-			correctPositions = append(correctPositions, uint(i))
-			newCandidates = filter(newCandidates, func(candidate uint) bool {
-				return getDigits(candidate, 5)[i] == feedbackPerDigit[i].digit
-			})
-		}
+// candidateSetFor resolves a --set flag value to a primel.CandidateSet. "file:path" reads the
+// candidate set from a file containing one number per line.
+func candidateSetFor(setFlag string) (primel.CandidateSet, error) {
+	if path, ok := strings.CutPrefix(setFlag, "file:"); ok {
+		return fileCandidateSet(path), nil
 	}
 
-	for i := 0; i < len(feedbackPerDigit); i++ {
-		switch feedbackPerDigit[i].feedbackType {
-		case feedbackTypeCorrect:
-			// Already processed
-			// Do nothing
-		case feedbackTypePresent:
-			newCandidates = filter(newCandidates, func(candidate uint) bool {
-				for index, digit := range getDigits(candidate, 5) {
-					if digit == feedbackPerDigit[i].digit && index != i && !contains(correctPositions, uint(index)) {
-						return true
-					}
-				}
-				return false
-			})
-		case feedbackTypeAbsent:
-			newCandidates = filter(newCandidates, func(candidate uint) bool {
-				for index, digit := range getDigits(candidate, 5) {
-					if digit == feedbackPerDigit[i].digit && !contains(correctPositions, uint(index)) {
-						return false
-					}
-				}
-				return true
-			})
-		default:
-			fmt.Fprintf(os.Stderr, "Unknown feedback type")
-			os.Exit(2)
-		}
+	switch setFlag {
+	case "primes":
+		return primel.Primes, nil
+	case "squares":
+		return primel.Squares, nil
+	case "fibonacci":
+		return primel.Fibonacci, nil
+	default:
+		return nil, fmt.Errorf("unknown candidate set: %s", setFlag)
 	}
-	return
 }
 
-func getPrimes(from uint, to uint) []uint {
-	primesTo := sieve(to)
-	var result []uint
-	for i := 0; i < len(primesTo); i++ {
-		if primesTo[i] >= from {
-			result = append(result, primesTo[i])
+// fileCandidateSet returns a CandidateSet that reads one number per line from path, keeping only
+// those within [from, to).
+func fileCandidateSet(path string) primel.CandidateSet {
+	return func(from, to uint) []uint {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Could not open candidate file %s: %v\n", path, err)
+			os.Exit(1)
 		}
-	}
-	return result
-}
+		defer file.Close()
 
-func sieve(max uint) []uint {
-	if max < 2 {
-		return []uint{}
-	}
-
-	var primes []uint
-	// Generate a list of all candidates where the value of the candidate the index + 2 and the
-	// boolean flag determines if a prime candidate is valid or not
-	candidates := make([]bool, max-2)
-	for i := 0; i < len(candidates); i++ {
-		candidates[i] = true
-	}
-	// Iterate over the prime candidates and invalidate multiples of each
-	for i := 0; i < len(candidates); i++ {
-		if candidates[i] {
-			primes = append(primes, uint(i+2))
-			// (i+2) is the value of the prime candidate
-			// (i+2) * 2 is 2 * the value of the prime candidate
-			// (i+2) * 2 - 2 is the index of the first multiple of the prime candidate
-			// This index is incremented by (i+2) to find the next multiple
-			for j := (i+2)*2 - 2; j < len(candidates); j += i + 2 {
-				candidates[j] = false
+		var result []uint
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			value, err := strconv.ParseUint(line, 10, 64)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid candidate %q in %s: %v\n", line, path, err)
+				os.Exit(1)
+			}
+			if uint(value) >= from && uint(value) < to {
+				result = append(result, uint(value))
 			}
 		}
+		return result
 	}
-
-	return primes
 }
 
-func getDigits(num uint, numberOfDigits uint) []uint {
-	var result []uint
-	for i := 0; i < int(numberOfDigits); i++ {
-		result = append(result, num%10)
-		num /= 10
+// solverFor resolves a --strategy flag value to the primel.Solver that implements it.
+func solverFor(strategyFlag string, digits uint) (primel.Solver, error) {
+	switch strategyFlag {
+	case "heuristic":
+		return primel.Heuristic{Digits: digits}, nil
+	case "entropy":
+		return primel.Entropy{Digits: digits}, nil
+	case "minimax":
+		return primel.Minimax{Digits: digits}, nil
+	default:
+		return nil, fmt.Errorf("unknown strategy: %s", strategyFlag)
 	}
-	return result
 }
 
-type feedback struct {
-	digit        uint
-	feedbackType feedbackType
+// bestGuessFor returns node.Guess when a precomputed tree node is available (an O(1) lookup),
+// otherwise it falls back to asking solver for the best guess from scratch.
+func bestGuessFor(candidates []uint, solver primel.Solver, node *decisionNode) uint {
+	if node != nil {
+		return node.Guess
+	}
+	return solver.Guess(candidates)
 }
 
-type feedbackType uint
-
-const (
-	feedbackTypeAbsent feedbackType = iota
-	feedbackTypePresent
-	feedbackTypeCorrect
-)
-
-func readFeedbackForDigits(guessDigits []uint) []feedback {
-	result := make([]feedback, len(guessDigits))
+// readScore prompts the user for the feedback on each digit of guess and returns it as a Score.
+func readScore(guess uint, digits uint) []primel.FeedbackType {
+	guessDigits := getDigits(guess, digits)
+	score := make([]primel.FeedbackType, digits)
 	reader := bufio.NewReader(os.Stdin)
 	for i := len(guessDigits) - 1; i >= 0; i-- {
 		fmt.Printf("Was the digit in position \033[1m%v\033[0m of the guess (", len(guessDigits)-i)
@@ -233,11 +227,11 @@ func readFeedbackForDigits(guessDigits []uint) []feedback {
 			text = strings.TrimSuffix(text, "\n")
 			switch text {
 			case "c":
-				result[i] = feedback{guessDigits[i], feedbackTypeCorrect}
+				score[i] = primel.Correct
 			case "p":
-				result[i] = feedback{guessDigits[i], feedbackTypePresent}
+				score[i] = primel.Present
 			case "a":
-				result[i] = feedback{guessDigits[i], feedbackTypeAbsent}
+				score[i] = primel.Absent
 			default:
 				fmt.Fprintf(os.Stderr, "Invalid feedback: %s\n", text)
 				continue
@@ -245,33 +239,26 @@ func readFeedbackForDigits(guessDigits []uint) []feedback {
 			break
 		}
 	}
-	return result
-}
-
-func filter(slice []uint, predicate func(uint) bool) []uint {
-	var newSlice []uint
-	for i := 0; i < len(slice); i++ {
-		if predicate(slice[i]) {
-			newSlice = append(newSlice, slice[i])
-		}
-	}
-	return newSlice
+	return score
 }
 
-func contains(slice []uint, elem uint) bool {
-	for i := 0; i < len(slice); i++ {
-		if slice[i] == elem {
-			return true
+// allCorrect reports whether score represents a fully-solved guess.
+func allCorrect(score []primel.FeedbackType) bool {
+	for _, f := range score {
+		if f != primel.Correct {
+			return false
 		}
 	}
-	return false
+	return true
 }
 
-func all(slice []feedback, predicate func(feedback) bool) bool {
-	for i := 0; i < len(slice); i++ {
-		if !predicate(slice[i]) {
-			return false
-		}
+// getDigits returns num's digits, least-significant first, matching primel's internal digit
+// order.
+func getDigits(num uint, numberOfDigits uint) []uint {
+	result := make([]uint, numberOfDigits)
+	for i := range result {
+		result[i] = num % 10
+		num /= 10
 	}
-	return true
+	return result
 }
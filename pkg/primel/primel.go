@@ -0,0 +1,247 @@
+// Package primel implements the solving logic for numeric Wordle-style games such as "Primel"
+// (https://converged.yt/primel/) as a reusable library: generating the candidate set, scoring a
+// guess against a solution, and filtering candidates given observed feedback. The primel-solver
+// command is a thin interactive wrapper around this package.
+package primel
+
+import "fmt"
+
+// FeedbackType describes how a single digit of a guess compares to the solution.
+type FeedbackType uint
+
+const (
+	Absent FeedbackType = iota
+	Present
+	Correct
+)
+
+// Feedback is the per-digit result of comparing a guess to a solution: which digit was guessed,
+// and whether it is absent, present or correct.
+type Feedback struct {
+	Digit uint
+	Type  FeedbackType
+}
+
+// CandidateSet generates the candidate numbers in the half-open range [from, to) that make up a
+// game's solution space, e.g. Primes or Squares.
+type CandidateSet func(from, to uint) []uint
+
+// Primes is a CandidateSet of prime numbers, the candidate set used by Primel itself.
+func Primes(from, to uint) []uint {
+	return getPrimes(from, to)
+}
+
+// Squares is a CandidateSet of perfect squares.
+func Squares(from, to uint) []uint {
+	var result []uint
+	for n := isqrt(from); n*n < to; n++ {
+		if square := n * n; square >= from {
+			result = append(result, square)
+		}
+	}
+	return result
+}
+
+// Fibonacci is a CandidateSet of Fibonacci numbers.
+func Fibonacci(from, to uint) []uint {
+	var result []uint
+	for a, b := uint(1), uint(1); a < to; a, b = b, a+b {
+		if a >= from {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+func isqrt(n uint) uint {
+	if n == 0 {
+		return 0
+	}
+	r := n
+	for r*r > n {
+		r = (r + n/r) / 2
+	}
+	for (r+1)*(r+1) <= n {
+		r++
+	}
+	return r
+}
+
+// GameConfig describes the parameters of a numeric-Wordle-style game: how many digits a
+// candidate has, the range candidates are drawn from, and which set they come from. Primel
+// itself is GameConfig{Digits: 5, From: 10000, To: 100000, Set: Primes}.
+type GameConfig struct {
+	Digits uint
+	From   uint
+	To     uint
+	Set    CandidateSet
+}
+
+// Candidates generates the candidate set described by c.
+func (c GameConfig) Candidates() []uint {
+	return c.Set(c.From, c.To)
+}
+
+// Validate reports whether c.From and c.To fit within c.Digits digits. getDigits always produces
+// exactly Digits digits, silently dropping high-order digits or zero-padding short ones, so a
+// range that doesn't fit (e.g. Digits: 1, To: 200) would otherwise make candidates indistinguishable
+// from their truncated digit representation.
+func (c GameConfig) Validate() error {
+	if c.Digits == 0 {
+		return fmt.Errorf("digits must be at least 1")
+	}
+
+	min, max := pow10(c.Digits-1), pow10(c.Digits)
+	if c.From < min || c.To > max {
+		return fmt.Errorf("range [%v, %v) does not fit in %v digits: expected a range within [%v, %v)", c.From, c.To, c.Digits, min, max)
+	}
+	return nil
+}
+
+// pow10 returns 10^n.
+func pow10(n uint) uint {
+	result := uint(1)
+	for i := uint(0); i < n; i++ {
+		result *= 10
+	}
+	return result
+}
+
+// Score compares guess against solution and returns the feedback for each of guess's digits
+// digits digits, in least-significant-digit-first order (matching getDigits).
+func Score(guess, solution, digits uint) []FeedbackType {
+	var guessBuf, solutionBuf = make([]uint, digits), make([]uint, digits)
+	feedbackPerDigit := scoreDigits(getDigits(guess, guessBuf), getDigits(solution, solutionBuf))
+	score := make([]FeedbackType, digits)
+	for i, f := range feedbackPerDigit {
+		score[i] = f.Type
+	}
+	return score
+}
+
+// Filter returns the subset of candidates that are still consistent with guess producing score.
+// The number of digits is taken from len(score).
+func Filter(candidates []uint, guess uint, score []FeedbackType) []uint {
+	digits := uint(len(score))
+	guessDigits := getDigits(guess, make([]uint, digits))
+	feedbackPerDigit := make([]Feedback, digits)
+	for i := range feedbackPerDigit {
+		feedbackPerDigit[i] = Feedback{Digit: guessDigits[i], Type: score[i]}
+	}
+	return incorporateFeedback(feedbackPerDigit, candidates)
+}
+
+// scoreDigits computes the feedback for each digit of guessDigits against solutionDigits.
+func scoreDigits(guessDigits []uint, solutionDigits []uint) []Feedback {
+	if len(guessDigits) != len(solutionDigits) {
+		panic("The length of the guess and the solution must be the same!")
+	}
+	feedbackPerDigit := make([]Feedback, len(guessDigits))
+
+	// Handle all correct digits first
+	for i := 0; i < len(guessDigits); i++ {
+		if guessDigits[i] == solutionDigits[i] {
+			feedbackPerDigit[i] = Feedback{Digit: guessDigits[i], Type: Correct}
+		}
+	}
+
+	// Handle remaining (present and absent) digits
+	for i := 0; i < len(guessDigits); i++ {
+		// Correct digits have already been handled
+		if feedbackPerDigit[i].Type == Correct {
+			continue
+		}
+
+		for j := 0; j < len(solutionDigits); j++ {
+			// Don't consider already correct digits to determine if the current digit is present
+			// or absent
+			if feedbackPerDigit[j].Type == Correct {
+				continue
+			} else if solutionDigits[j] == guessDigits[i] {
+				feedbackPerDigit[i] = Feedback{Digit: guessDigits[i], Type: Present}
+				break
+			} else if j == len(solutionDigits)-1 {
+				feedbackPerDigit[i] = Feedback{Digit: guessDigits[i], Type: Absent}
+			}
+		}
+	}
+
+	return feedbackPerDigit
+}
+
+// incorporateFeedback returns the subset of candidates still consistent with feedbackPerDigit.
+func incorporateFeedback(feedbackPerDigit []Feedback, candidates []uint) (newCandidates []uint) {
+	digits := uint(len(feedbackPerDigit))
+	newCandidates = make([]uint, len(candidates))
+	copy(newCandidates, candidates)
+	var correctPositions []uint
+
+	// Process correct feedbacks first as they affect the other feedbacks
+	for i := 0; i < len(feedbackPerDigit); i++ {
+		if feedbackPerDigit[i].Type == Correct {
+			correctPositions = append(correctPositions, uint(i))
+			buf := make([]uint, digits)
+			newCandidates = filter(newCandidates, func(candidate uint) bool {
+				return getDigits(candidate, buf)[i] == feedbackPerDigit[i].Digit
+			})
+		}
+	}
+
+	for i := 0; i < len(feedbackPerDigit); i++ {
+		buf := make([]uint, digits)
+		switch feedbackPerDigit[i].Type {
+		case Correct:
+			// Already processed
+			// Do nothing
+		case Present:
+			newCandidates = filter(newCandidates, func(candidate uint) bool {
+				for index, digit := range getDigits(candidate, buf) {
+					if digit == feedbackPerDigit[i].Digit && index != i && !contains(correctPositions, uint(index)) {
+						return true
+					}
+				}
+				return false
+			})
+		case Absent:
+			newCandidates = filter(newCandidates, func(candidate uint) bool {
+				for index, digit := range getDigits(candidate, buf) {
+					if digit == feedbackPerDigit[i].Digit && !contains(correctPositions, uint(index)) {
+						return false
+					}
+				}
+				return true
+			})
+		}
+	}
+	return
+}
+
+// getDigits fills buf with num's digits, least-significant first, and returns it. The caller
+// provides buf (sized to the number of digits) so that hot loops can reuse a single buffer
+// instead of allocating one per call.
+func getDigits(num uint, buf []uint) []uint {
+	for i := range buf {
+		buf[i] = num % 10
+		num /= 10
+	}
+	return buf
+}
+
+func filter(slice []uint, predicate func(uint) bool) []uint {
+	var newSlice []uint
+	for i := 0; i < len(slice); i++ {
+		if predicate(slice[i]) {
+			newSlice = append(newSlice, slice[i])
+		}
+	}
+	return newSlice
+}
+
+func contains(slice []uint, elem uint) bool {
+	for i := 0; i < len(slice); i++ {
+		if slice[i] == elem {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package primel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		guess, solution uint
+		want            []FeedbackType
+	}{
+		// 12953 vs 12953: every digit correct.
+		{12953, 12953, []FeedbackType{Correct, Correct, Correct, Correct, Correct}},
+		// 12953 vs 13952: 5, 9 and 1 correct; 3 and 2 present but misplaced.
+		{12953, 13952, []FeedbackType{Present, Correct, Correct, Present, Correct}},
+		// 11111 vs 22222: every digit absent.
+		{11111, 22222, []FeedbackType{Absent, Absent, Absent, Absent, Absent}},
+	}
+
+	for _, tt := range tests {
+		got := Score(tt.guess, tt.solution, 5)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Score(%v, %v, 5) = %v, want %v", tt.guess, tt.solution, got, tt.want)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	candidates := []uint{12953, 13952, 22222, 11111}
+	score := Score(12953, 12953, 5)
+
+	got := Filter(candidates, 12953, score)
+	want := []uint{12953}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter(%v, 12953, %v) = %v, want %v", candidates, score, got, want)
+	}
+}
+
+func TestCandidateSets(t *testing.T) {
+	primes := Primes(10, 30)
+	if want := []uint{11, 13, 17, 19, 23, 29}; !reflect.DeepEqual(primes, want) {
+		t.Errorf("Primes(10, 30) = %v, want %v", primes, want)
+	}
+
+	squares := Squares(10, 30)
+	if want := []uint{16, 25}; !reflect.DeepEqual(squares, want) {
+		t.Errorf("Squares(10, 30) = %v, want %v", squares, want)
+	}
+
+	fib := Fibonacci(1, 10)
+	if want := []uint{1, 1, 2, 3, 5, 8}; !reflect.DeepEqual(fib, want) {
+		t.Errorf("Fibonacci(1, 10) = %v, want %v", fib, want)
+	}
+}
+
+func TestGameConfigCandidates(t *testing.T) {
+	config := GameConfig{Digits: 2, From: 10, To: 30, Set: Primes}
+	if got, want := config.Candidates(), []uint{11, 13, 17, 19, 23, 29}; !reflect.DeepEqual(got, want) {
+		t.Errorf("config.Candidates() = %v, want %v", got, want)
+	}
+}
+
+func TestGameConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  GameConfig
+		wantErr bool
+	}{
+		{"fits exactly", GameConfig{Digits: 5, From: 10000, To: 100000}, false},
+		{"fits within bounds", GameConfig{Digits: 2, From: 10, To: 30}, false},
+		{"zero digits", GameConfig{Digits: 0, From: 0, To: 10}, true},
+		{"to exceeds digits", GameConfig{Digits: 1, From: 10, To: 200}, true},
+		{"from below digits", GameConfig{Digits: 2, From: 1, To: 100}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.config.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: %v.Validate() error = %v, wantErr %v", tt.name, tt.config, err, tt.wantErr)
+		}
+	}
+}
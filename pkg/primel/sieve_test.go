@@ -0,0 +1,46 @@
+package primel
+
+import "testing"
+
+func TestPrimesInRange(t *testing.T) {
+	want := []uint{11, 13, 17, 19, 23, 29}
+
+	var got []uint
+	for p := range PrimesInRange(10, 30) {
+		got = append(got, p)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("PrimesInRange(10, 30) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("PrimesInRange(10, 30) = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestPrimesInRangeMatchesGetPrimesAcrossASegmentBoundary(t *testing.T) {
+	// Sieve windows are segmentSpan wide and laid out starting at from, so a range wider than
+	// segmentSpan straddles a window boundary (here at 2*segmentSpan-50), exercising the
+	// per-window start offsets of the base primes.
+	from, to := uint(segmentSpan-50), uint(2*segmentSpan+50)
+
+	want := getPrimes(from, to)
+
+	var got []uint
+	for p := range PrimesInRange(from, to) {
+		got = append(got, p)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("PrimesInRange(%v, %v) returned %v primes, want %v", from, to, len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("PrimesInRange(%v, %v)[%v] = %v, want %v", from, to, i, got[i], want[i])
+			break
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package primel
+
+import "fmt"
+
+// Stats summarizes the result of running a Solver against every candidate solution of a
+// GameConfig.
+//
+// SimulateAll plays one game per candidate, each game makes up to O(N) calls to Solver.Guess, and
+// each of those is itself O(N^2) (Entropy, Minimax) or O(N^3) (Heuristic) in the number of
+// candidates N, so SimulateAll as a whole is at least O(N^3), or O(N^4) with Heuristic, and more
+// when games run long. This is fine for small
+// GameConfigs (a handful of digits, a custom candidate file, ...) but is not practical at the
+// full ~8000-candidate Primel scale with Entropy or Minimax; use a precomputed decision tree
+// (see the precompute subcommand) rather than SimulateAll for scoring strategies at that scale.
+type Stats struct {
+	// Distribution maps number of guesses taken to the number of solutions solved in that many
+	// guesses.
+	Distribution map[int]int
+	Average      float64
+	Worst        int
+	Unsolvable   int
+}
+
+// Simulate plays a full game against solution using solver over config's candidate set,
+// returning every guess made in order. It returns an error if the candidate set is exhausted
+// before solution is found, which should only happen if solution is not itself a valid
+// candidate.
+func Simulate(config GameConfig, solver Solver, solution uint) (guesses []uint, err error) {
+	return simulate(config.Candidates(), config.Digits, solver, solution)
+}
+
+// simulate plays a full game against solution using solver, starting from candidates. It is
+// shared by Simulate and SimulateAll so that SimulateAll only has to generate the candidate set
+// once instead of re-running config.Candidates() for every solution it simulates.
+func simulate(candidates []uint, digits uint, solver Solver, solution uint) (guesses []uint, err error) {
+	for {
+		if len(candidates) == 0 {
+			return guesses, fmt.Errorf("no remaining candidates are consistent with the feedback observed so far")
+		}
+
+		guess := solver.Guess(candidates)
+		guesses = append(guesses, guess)
+		if guess == solution {
+			return guesses, nil
+		}
+
+		candidates = Filter(candidates, guess, Score(guess, solution, digits))
+	}
+}
+
+// SimulateAll runs a game against every candidate solution of config and reports the
+// distribution of the number of guesses taken, the average and worst case, and how many
+// solutions could not be solved. See Stats for its time complexity.
+func SimulateAll(config GameConfig, solver Solver) Stats {
+	candidates := config.Candidates()
+
+	stats := Stats{Distribution: make(map[int]int)}
+	var totalGuesses, solvedCount int
+	for i := 0; i < len(candidates); i++ {
+		guesses, err := simulate(candidates, config.Digits, solver, candidates[i])
+		if err != nil {
+			stats.Unsolvable++
+			continue
+		}
+
+		turns := len(guesses)
+		stats.Distribution[turns]++
+		totalGuesses += turns
+		solvedCount++
+		if turns > stats.Worst {
+			stats.Worst = turns
+		}
+	}
+
+	if solvedCount > 0 {
+		stats.Average = float64(totalGuesses) / float64(solvedCount)
+	}
+	return stats
+}
@@ -0,0 +1,49 @@
+package primel
+
+import "testing"
+
+// simulateConfig is small enough that SimulateAll's O(N^3)-or-worse cost stays well under a second
+// even with Entropy or Minimax.
+var simulateConfig = GameConfig{Digits: 2, From: 10, To: 100, Set: Primes}
+
+func TestSimulateFindsTheSolution(t *testing.T) {
+	candidates := simulateConfig.Candidates()
+	solution := candidates[len(candidates)/2]
+
+	guesses, err := Simulate(simulateConfig, Entropy{Digits: simulateConfig.Digits}, solution)
+	if err != nil {
+		t.Fatalf("Simulate(%v, Entropy{}, %v) returned an error: %v", simulateConfig, solution, err)
+	}
+	if len(guesses) == 0 || guesses[len(guesses)-1] != solution {
+		t.Errorf("Simulate(%v, Entropy{}, %v) = %v, want it to end on %v", simulateConfig, solution, guesses, solution)
+	}
+}
+
+func TestSimulateRejectsASolutionOutsideTheCandidateSet(t *testing.T) {
+	_, err := Simulate(simulateConfig, Entropy{Digits: simulateConfig.Digits}, 4)
+	if err == nil {
+		t.Errorf("Simulate(%v, Entropy{}, 4) = nil error, want an error since 4 is not a candidate", simulateConfig)
+	}
+}
+
+func TestSimulateAllSolvesEveryCandidate(t *testing.T) {
+	candidates := simulateConfig.Candidates()
+
+	stats := SimulateAll(simulateConfig, Entropy{Digits: simulateConfig.Digits})
+
+	if stats.Unsolvable != 0 {
+		t.Errorf("SimulateAll(%v, Entropy{}).Unsolvable = %v, want 0", simulateConfig, stats.Unsolvable)
+	}
+
+	var solved int
+	for _, count := range stats.Distribution {
+		solved += count
+	}
+	if solved != len(candidates) {
+		t.Errorf("SimulateAll(%v, Entropy{}) solved %v candidates, want %v", simulateConfig, solved, len(candidates))
+	}
+
+	if stats.Average <= 0 || stats.Average > float64(stats.Worst) {
+		t.Errorf("SimulateAll(%v, Entropy{}).Average = %v, want a value in (0, %v]", simulateConfig, stats.Average, stats.Worst)
+	}
+}
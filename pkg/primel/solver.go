@@ -0,0 +1,176 @@
+package primel
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// Solver picks the next guess to make given the set of remaining candidates.
+type Solver interface {
+	Guess(candidates []uint) uint
+}
+
+// Heuristic is the original "sum of remaining candidates" scorer: it picks the guess that
+// minimizes the total number of candidates left across every possible solution. It is not
+// optimal and is kept mostly for comparison, since it re-scans all candidates inside its scoring
+// step for every possible solution (effectively O(N^3)).
+type Heuristic struct{ Digits uint }
+
+// Entropy picks the guess that maximizes the Shannon entropy of the feedback-pattern
+// distribution it induces over candidates, i.e. the guess that is expected to narrow down the
+// candidate set the most.
+type Entropy struct{ Digits uint }
+
+// Minimax picks the guess that minimizes the size of the largest feedback-pattern bucket it
+// induces over candidates, guaranteeing the best worst-case reduction.
+type Minimax struct{ Digits uint }
+
+func (s Heuristic) Guess(candidates []uint) uint {
+	var bestGuess uint
+	bestGuessValue := uint64(math.MaxUint64)
+	for i := 0; i < len(candidates); i++ {
+		candidateGuess := candidates[i]
+		guessValue := evaluateGuess(candidateGuess, candidates, s.Digits)
+		if guessValue < bestGuessValue {
+			bestGuess = candidateGuess
+			bestGuessValue = guessValue
+		}
+	}
+	return bestGuess
+}
+
+// Ties are broken in favor of the first guess found, which is always itself a candidate since
+// guesses are only ever drawn from the candidate set, so the last guess is always able to win
+// the game.
+func (s Entropy) Guess(candidates []uint) uint {
+	var bestGuess uint
+	bestEntropy := -1.0
+	for i := 0; i < len(candidates); i++ {
+		candidateGuess := candidates[i]
+		entropy := entropyOf(bucketByFeedback(candidateGuess, candidates, s.Digits))
+		if entropy > bestEntropy {
+			bestGuess = candidateGuess
+			bestEntropy = entropy
+		}
+	}
+	return bestGuess
+}
+
+func (s Minimax) Guess(candidates []uint) uint {
+	var bestGuess uint
+	bestWorstBucket := -1
+	for i := 0; i < len(candidates); i++ {
+		candidateGuess := candidates[i]
+		worstBucket := 0
+		for _, count := range bucketByFeedback(candidateGuess, candidates, s.Digits) {
+			if count > worstBucket {
+				worstBucket = count
+			}
+		}
+		if bestWorstBucket == -1 || worstBucket < bestWorstBucket {
+			bestGuess = candidateGuess
+			bestWorstBucket = worstBucket
+		}
+	}
+	return bestGuess
+}
+
+func evaluateGuess(guess uint, candidates []uint, digits uint) uint64 {
+	var remainingCandidatesAfterGuess uint64
+	var wg sync.WaitGroup
+	for i := 0; i < len(candidates); i++ {
+		possibleSolution := candidates[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			feedbackPerDigit := scoreDigits(getDigits(guess, make([]uint, digits)), getDigits(possibleSolution, make([]uint, digits)))
+			newCandidates := incorporateFeedback(feedbackPerDigit, candidates)
+			atomic.AddUint64(&remainingCandidatesAfterGuess, uint64(len(newCandidates)))
+		}()
+	}
+	wg.Wait()
+	return remainingCandidatesAfterGuess
+}
+
+// FeedbackPatternCount returns the number of distinct feedback patterns for a guess with the
+// given number of digits: each digit's feedback is one of 3 values (Absent, Present, Correct),
+// so there are 3^digits possible patterns.
+func FeedbackPatternCount(digits uint) int {
+	count := 1
+	for i := uint(0); i < digits; i++ {
+		count *= 3
+	}
+	return count
+}
+
+// AllCorrectPattern is the feedback pattern produced when every digit is correct, i.e. the guess
+// equals the solution.
+func AllCorrectPattern(digits uint) int {
+	return FeedbackPatternCount(digits) - 1
+}
+
+// FeedbackPattern encodes a feedback slice as a base-3 index, one trit per digit.
+func FeedbackPattern(score []FeedbackType) int {
+	pattern := 0
+	for i := 0; i < len(score); i++ {
+		pattern = pattern*3 + int(score[i])
+	}
+	return pattern
+}
+
+// PartitionByFeedback groups candidates by the feedback pattern that guess produces against each
+// of them.
+func PartitionByFeedback(guess uint, candidates []uint, digits uint) map[int][]uint {
+	buckets := make(map[int][]uint)
+	guessDigits := getDigits(guess, make([]uint, digits))
+	solutionBuf := make([]uint, digits)
+	for i := 0; i < len(candidates); i++ {
+		feedbackPerDigit := scoreDigits(guessDigits, getDigits(candidates[i], solutionBuf))
+		pattern := feedbackPattern(feedbackPerDigit)
+		buckets[pattern] = append(buckets[pattern], candidates[i])
+	}
+	return buckets
+}
+
+// bucketByFeedback partitions candidates by the feedback pattern that guess would produce
+// against each of them, without incorporateFeedback's per-candidate re-scan of the whole
+// candidate set: it only needs scoreDigits per (guess, solution) pair plus an increment.
+func bucketByFeedback(guess uint, candidates []uint, digits uint) map[int]int {
+	buckets := make(map[int]int, FeedbackPatternCount(digits))
+	guessDigits := getDigits(guess, make([]uint, digits))
+	solutionBuf := make([]uint, digits)
+	for i := 0; i < len(candidates); i++ {
+		feedbackPerDigit := scoreDigits(guessDigits, getDigits(candidates[i], solutionBuf))
+		buckets[feedbackPattern(feedbackPerDigit)]++
+	}
+	return buckets
+}
+
+// feedbackPattern encodes a []Feedback as a base-3 index, one trit per digit.
+func feedbackPattern(feedbackPerDigit []Feedback) int {
+	pattern := 0
+	for i := 0; i < len(feedbackPerDigit); i++ {
+		pattern = pattern*3 + int(feedbackPerDigit[i].Type)
+	}
+	return pattern
+}
+
+// entropyOf computes the Shannon entropy (in bits) of the distribution of candidates across
+// buckets.
+func entropyOf(buckets map[int]int) float64 {
+	var total int
+	for _, count := range buckets {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	var entropy float64
+	for _, count := range buckets {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
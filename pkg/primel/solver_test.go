@@ -0,0 +1,69 @@
+package primel
+
+import "testing"
+
+// solverCandidates is small enough that every strategy below can be reasoned about by hand.
+var solverCandidates = Squares(100, 1000)
+
+func TestSolversReturnACandidate(t *testing.T) {
+	solvers := map[string]Solver{
+		"Heuristic": Heuristic{Digits: 3},
+		"Entropy":   Entropy{Digits: 3},
+		"Minimax":   Minimax{Digits: 3},
+	}
+
+	for name, solver := range solvers {
+		guess := solver.Guess(solverCandidates)
+		if !contains(solverCandidates, guess) {
+			t.Errorf("%s.Guess(%v) = %v, want a candidate from the list", name, solverCandidates, guess)
+		}
+	}
+}
+
+func TestHeuristicPrefersTheGuessWithFewerRemainingCandidates(t *testing.T) {
+	// 484 leaves 3 candidates remaining summed across every possible solution, while 121 and 144
+	// leave 4 each (121 and 144 share a digit in the same position, so guessing either one leaves
+	// both of them in the running against the other), so 484 is the unique minimizer.
+	candidates := []uint{121, 144, 484}
+	got := Heuristic{Digits: 3}.Guess(candidates)
+	if got != 484 {
+		t.Errorf("Heuristic{}.Guess(%v) = %v, want 484", candidates, got)
+	}
+}
+
+func TestFeedbackPatternRoundTrips(t *testing.T) {
+	for pattern := 0; pattern < FeedbackPatternCount(3); pattern++ {
+		score := make([]FeedbackType, 3)
+		value := pattern
+		for i := len(score) - 1; i >= 0; i-- {
+			score[i] = FeedbackType(value % 3)
+			value /= 3
+		}
+		if got := FeedbackPattern(score); got != pattern {
+			t.Errorf("FeedbackPattern(%v) = %v, want %v", score, got, pattern)
+		}
+	}
+}
+
+func TestAllCorrectPattern(t *testing.T) {
+	score := []FeedbackType{Correct, Correct, Correct}
+	if got, want := FeedbackPattern(score), AllCorrectPattern(3); got != want {
+		t.Errorf("FeedbackPattern(%v) = %v, want AllCorrectPattern(3) = %v", score, got, want)
+	}
+}
+
+func TestPartitionByFeedback(t *testing.T) {
+	candidates := []uint{121, 144, 484}
+	buckets := PartitionByFeedback(121, candidates, 3)
+
+	var total int
+	for _, bucket := range buckets {
+		total += len(bucket)
+	}
+	if total != len(candidates) {
+		t.Errorf("PartitionByFeedback(121, %v, 3) buckets contain %v candidates in total, want %v", candidates, total, len(candidates))
+	}
+	if got := buckets[AllCorrectPattern(3)]; len(got) != 1 || got[0] != 121 {
+		t.Errorf("PartitionByFeedback(121, %v, 3)[AllCorrectPattern(3)] = %v, want [121]", candidates, got)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/gob"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"os"
+
+	"github.com/Gadiguibou/primel-solver/pkg/primel"
+)
+
+// treeFormatVersion is bumped whenever the on-disk layout of decisionTree changes, so that an
+// old tree file fails the header check below instead of being misread.
+const treeFormatVersion = 1
+
+// decisionTree is the full, precomputed game tree for a candidate set: the root guess, plus a
+// child node for every feedback pattern it can produce. It is serialized with encoding/gob.
+//
+// Digits and CandidateHash let loadDecisionTree reject a tree that was built for a different
+// candidate set (e.g. after the digit count, range or candidate set changed) instead of silently
+// traversing a stale tree. Digits is checked explicitly because it also determines how feedback
+// patterns are encoded, so a mismatch there is never safe to paper over even if CandidateHash
+// happened to collide.
+type decisionTree struct {
+	Version        int
+	Digits         uint
+	CandidateHash  uint64
+	CandidateCount int
+	Root           *decisionNode
+}
+
+// decisionNode is one guess in the tree, plus the child to move to for every feedback pattern
+// that guess can produce against a remaining candidate. The all-correct pattern is never stored,
+// since the game ends as soon as that feedback is observed.
+type decisionNode struct {
+	Guess    uint
+	Children map[int]*decisionNode
+}
+
+// runPrecompute implements the `precompute` subcommand: it builds the full decision tree for the
+// current candidate set using the chosen strategy and writes it to --tree.
+func runPrecompute(args []string) {
+	fs := flag.NewFlagSet("precompute", flag.ExitOnError)
+	strategyFlag := fs.String("strategy", "entropy", "guess-scoring strategy to use when building the tree: entropy, minimax or heuristic")
+	treeFlag := fs.String("tree", "primel.tree", "path to write the precomputed decision tree to")
+	digitsFlag := fs.Uint("digits", 5, "number of digits in a candidate")
+	rangeFlag := fs.String("range", "10000..100000", "half-open range of candidate values, as \"from..to\"")
+	setFlag := fs.String("set", "primes", "candidate set to draw guesses from: primes, squares, fibonacci, or file:path")
+	fs.Parse(args)
+
+	config, err := gameConfigFor(*digitsFlag, *rangeFlag, *setFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	solver, err := solverFor(*strategyFlag, config.Digits)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	candidates := config.Candidates()
+	fmt.Printf("Building the %s decision tree for %v candidates...\n", *strategyFlag, len(candidates))
+
+	tree := &decisionTree{
+		Version:        treeFormatVersion,
+		Digits:         config.Digits,
+		CandidateHash:  candidateHash(candidates),
+		CandidateCount: len(candidates),
+		Root:           buildDecisionNode(candidates, solver, config.Digits),
+	}
+
+	if err := saveDecisionTree(tree, *treeFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not save decision tree: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote decision tree to %s\n", *treeFlag)
+}
+
+// buildDecisionNode recursively builds the decision tree for candidates: it picks the best guess
+// according to solver, partitions candidates by the feedback pattern that guess produces, and
+// recurses into each non-trivial bucket.
+func buildDecisionNode(candidates []uint, solver primel.Solver, digits uint) *decisionNode {
+	guess := solver.Guess(candidates)
+	buckets := primel.PartitionByFeedback(guess, candidates, digits)
+
+	node := &decisionNode{Guess: guess, Children: make(map[int]*decisionNode, len(buckets))}
+	for pattern, bucketCandidates := range buckets {
+		if pattern == primel.AllCorrectPattern(digits) {
+			// Observing this feedback ends the game; there is nothing left to precompute.
+			continue
+		}
+		if len(bucketCandidates) == 1 {
+			node.Children[pattern] = &decisionNode{Guess: bucketCandidates[0]}
+			continue
+		}
+		node.Children[pattern] = buildDecisionNode(bucketCandidates, solver, digits)
+	}
+	return node
+}
+
+// candidateHash hashes the candidate set so a decision tree can be tied to the exact set of
+// candidates it was built for.
+func candidateHash(candidates []uint) uint64 {
+	h := fnv.New64a()
+	for i := 0; i < len(candidates); i++ {
+		c := candidates[i]
+		for j := 0; j < 8; j++ {
+			h.Write([]byte{byte(c)})
+			c >>= 8
+		}
+	}
+	return h.Sum64()
+}
+
+func saveDecisionTree(tree *decisionTree, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return gob.NewEncoder(file).Encode(tree)
+}
+
+// loadDecisionTree reads a decision tree from path and rejects it if it was built from a
+// different format version, digit count, or candidate set than digits and candidates.
+func loadDecisionTree(path string, digits uint, candidates []uint) (*decisionTree, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var tree decisionTree
+	if err := gob.NewDecoder(file).Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	if tree.Version != treeFormatVersion {
+		return nil, fmt.Errorf("tree format version %v does not match the expected version %v; rebuild it with the precompute subcommand", tree.Version, treeFormatVersion)
+	}
+	if tree.Digits != digits {
+		return nil, fmt.Errorf("tree was built for %v-digit candidates, not %v; rebuild it with the precompute subcommand", tree.Digits, digits)
+	}
+	if tree.CandidateHash != candidateHash(candidates) {
+		return nil, fmt.Errorf("tree was built for a different candidate set; rebuild it with the precompute subcommand")
+	}
+	return &tree, nil
+}
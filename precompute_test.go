@@ -0,0 +1,124 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Gadiguibou/primel-solver/pkg/primel"
+)
+
+func TestSaveAndLoadDecisionTreeRoundTrips(t *testing.T) {
+	candidates := []uint{11, 13, 17, 19}
+	tree := &decisionTree{
+		Version:        treeFormatVersion,
+		Digits:         2,
+		CandidateHash:  candidateHash(candidates),
+		CandidateCount: len(candidates),
+		Root:           &decisionNode{Guess: 11, Children: map[int]*decisionNode{0: {Guess: 13}}},
+	}
+
+	path := filepath.Join(t.TempDir(), "primel.tree")
+	if err := saveDecisionTree(tree, path); err != nil {
+		t.Fatalf("saveDecisionTree(%v, %q) returned an error: %v", tree, path, err)
+	}
+
+	got, err := loadDecisionTree(path, 2, candidates)
+	if err != nil {
+		t.Fatalf("loadDecisionTree(%q, 2, %v) returned an error: %v", path, candidates, err)
+	}
+	if got.Root.Guess != tree.Root.Guess || got.Root.Children[0].Guess != tree.Root.Children[0].Guess {
+		t.Errorf("loadDecisionTree(%q, 2, %v) = %+v, want a tree matching %+v", path, candidates, got, tree)
+	}
+}
+
+func TestLoadDecisionTreeRejectsAStaleVersion(t *testing.T) {
+	candidates := []uint{11, 13, 17, 19}
+	tree := &decisionTree{
+		Version:       treeFormatVersion - 1,
+		Digits:        2,
+		CandidateHash: candidateHash(candidates),
+		Root:          &decisionNode{Guess: 11},
+	}
+
+	path := filepath.Join(t.TempDir(), "primel.tree")
+	if err := saveDecisionTree(tree, path); err != nil {
+		t.Fatalf("saveDecisionTree(%v, %q) returned an error: %v", tree, path, err)
+	}
+
+	if _, err := loadDecisionTree(path, 2, candidates); err == nil {
+		t.Errorf("loadDecisionTree(%q, 2, %v) = nil error, want an error for a stale version", path, candidates)
+	}
+}
+
+func TestLoadDecisionTreeRejectsAMismatchedDigitCount(t *testing.T) {
+	candidates := []uint{11, 13, 17, 19}
+	tree := &decisionTree{
+		Version:       treeFormatVersion,
+		Digits:        2,
+		CandidateHash: candidateHash(candidates),
+		Root:          &decisionNode{Guess: 11},
+	}
+
+	path := filepath.Join(t.TempDir(), "primel.tree")
+	if err := saveDecisionTree(tree, path); err != nil {
+		t.Fatalf("saveDecisionTree(%v, %q) returned an error: %v", tree, path, err)
+	}
+
+	if _, err := loadDecisionTree(path, 3, candidates); err == nil {
+		t.Errorf("loadDecisionTree(%q, 3, %v) = nil error, want an error for a mismatched digit count", path, candidates)
+	}
+}
+
+func TestLoadDecisionTreeRejectsAMismatchedCandidateSet(t *testing.T) {
+	candidates := []uint{11, 13, 17, 19}
+	tree := &decisionTree{
+		Version:       treeFormatVersion,
+		Digits:        2,
+		CandidateHash: candidateHash(candidates),
+		Root:          &decisionNode{Guess: 11},
+	}
+
+	path := filepath.Join(t.TempDir(), "primel.tree")
+	if err := saveDecisionTree(tree, path); err != nil {
+		t.Fatalf("saveDecisionTree(%v, %q) returned an error: %v", tree, path, err)
+	}
+
+	otherCandidates := []uint{23, 29, 31, 37}
+	if _, err := loadDecisionTree(path, 2, otherCandidates); err == nil {
+		t.Errorf("loadDecisionTree(%q, 2, %v) = nil error, want an error for a mismatched candidate set", path, otherCandidates)
+	}
+}
+
+func TestBuildDecisionNodeCoversEveryCandidate(t *testing.T) {
+	candidates := []uint{11, 13, 17, 19}
+	node := buildDecisionNode(candidates, primel.Entropy{Digits: 2}, 2)
+
+	if !containsCandidate(candidates, node.Guess) {
+		t.Fatalf("buildDecisionNode(%v, Entropy{}, 2).Guess = %v, want a candidate from the list", candidates, node.Guess)
+	}
+
+	seen := map[uint]bool{node.Guess: true}
+	var visit func(*decisionNode)
+	visit = func(n *decisionNode) {
+		for _, child := range n.Children {
+			seen[child.Guess] = true
+			if child.Children != nil {
+				visit(child)
+			}
+		}
+	}
+	visit(node)
+
+	if len(seen) != len(candidates) {
+		t.Errorf("buildDecisionNode(%v, Entropy{}, 2) visits %v distinct candidates, want %v", candidates, len(seen), len(candidates))
+	}
+}
+
+func containsCandidate(candidates []uint, guess uint) bool {
+	for _, c := range candidates {
+		if c == guess {
+			return true
+		}
+	}
+	return false
+}